@@ -0,0 +1,391 @@
+package healops
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// PersistentBuffer spools log batches that didn't fit in the in-memory queue
+// to a size-capped directory of length-prefixed JSON files, so a transient
+// outage degrades to "slow" instead of "silently dropped".
+//
+// Files are named by creation order ("%020d.log") so Drain can replay them
+// oldest-first. Each record is a 4-byte big-endian length prefix followed by
+// the JSON-encoded batch.
+type PersistentBuffer struct {
+	dir          string
+	maxDiskBytes int64
+
+	mu       sync.Mutex
+	curFile  *os.File
+	curSize  int64
+	diskSize int64
+	seq      int64
+}
+
+// NewPersistentBuffer creates (if needed) dir and returns a PersistentBuffer
+// capped at maxDiskBytes. A maxDiskBytes <= 0 disables the cap.
+func NewPersistentBuffer(dir string, maxDiskBytes int64) (*PersistentBuffer, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("healops: creating disk buffer dir: %w", err)
+	}
+
+	b := &PersistentBuffer{dir: dir, maxDiskBytes: maxDiskBytes}
+	if err := b.scanExisting(); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func (b *PersistentBuffer) scanExisting() error {
+	entries, err := os.ReadDir(b.dir)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		b.diskSize += info.Size()
+		var seq int64
+		if _, err := fmt.Sscanf(e.Name(), "%020d.log", &seq); err == nil && seq >= b.seq {
+			b.seq = seq + 1
+		}
+	}
+	return nil
+}
+
+// Persist spools a batch of LogPayloads to disk, rotating the active file
+// once it exceeds rotateSize and evicting the oldest files until the total
+// footprint is back under MaxDiskBytes.
+func (b *PersistentBuffer) Persist(logs []LogPayload) error {
+	data, err := json.Marshal(logs)
+	if err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.curFile == nil || b.curSize >= persistentBufferRotateSize {
+		if err := b.rotateLocked(); err != nil {
+			return err
+		}
+	}
+
+	n, err := b.writeRecordLocked(data)
+	if err != nil {
+		return err
+	}
+	b.curSize += n
+	b.diskSize += n
+
+	return b.evictLocked()
+}
+
+const persistentBufferRotateSize = 4 * 1024 * 1024 // 4MB per file
+
+func (b *PersistentBuffer) rotateLocked() error {
+	if b.curFile != nil {
+		b.curFile.Close()
+	}
+	path := filepath.Join(b.dir, fmt.Sprintf("%020d.log", b.seq))
+	b.seq++
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("healops: rotating disk buffer: %w", err)
+	}
+	b.curFile = f
+	b.curSize = 0
+	return nil
+}
+
+func (b *PersistentBuffer) writeRecordLocked(data []byte) (int64, error) {
+	var lenPrefix [4]byte
+	binary.BigEndian.PutUint32(lenPrefix[:], uint32(len(data)))
+	if _, err := b.curFile.Write(lenPrefix[:]); err != nil {
+		return 0, err
+	}
+	if _, err := b.curFile.Write(data); err != nil {
+		return 0, err
+	}
+	return int64(len(data) + 4), nil
+}
+
+// evictLocked removes the oldest spooled files until diskSize is back under
+// maxDiskBytes. The currently-open file is never evicted.
+func (b *PersistentBuffer) evictLocked() error {
+	if b.maxDiskBytes <= 0 || b.diskSize <= b.maxDiskBytes {
+		return nil
+	}
+
+	files, err := b.sortedFilesLocked()
+	if err != nil {
+		return err
+	}
+
+	curName := ""
+	if b.curFile != nil {
+		curName = filepath.Base(b.curFile.Name())
+	}
+
+	for _, f := range files {
+		if b.diskSize <= b.maxDiskBytes {
+			break
+		}
+		if f.name == curName {
+			continue
+		}
+		if err := os.Remove(filepath.Join(b.dir, f.name)); err != nil {
+			continue
+		}
+		b.diskSize -= f.size
+	}
+	return nil
+}
+
+type bufferedFile struct {
+	name string
+	size int64
+}
+
+func (b *PersistentBuffer) sortedFilesLocked() ([]bufferedFile, error) {
+	entries, err := os.ReadDir(b.dir)
+	if err != nil {
+		return nil, err
+	}
+	files := make([]bufferedFile, 0, len(entries))
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, bufferedFile{name: e.Name(), size: info.Size()})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].name < files[j].name })
+	return files, nil
+}
+
+// Drain calls fn with each spooled batch, oldest file first, deleting each
+// file once fn returns nil. It stops at the first error returned by fn so
+// that batch can be retried on the next Drain call.
+func (b *PersistentBuffer) Drain(fn func([]LogPayload) error) error {
+	b.mu.Lock()
+	files, err := b.sortedFilesLocked()
+	curName := ""
+	if b.curFile != nil {
+		curName = filepath.Base(b.curFile.Name())
+	}
+	b.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	for _, f := range files {
+		if f.name == curName {
+			continue // still being written to
+		}
+		if err := b.drainFile(f.name, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *PersistentBuffer) drainFile(name string, fn func([]LogPayload) error) error {
+	path := filepath.Join(b.dir, name)
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	for {
+		var lenPrefix [4]byte
+		if _, err := io.ReadFull(r, lenPrefix[:]); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return err
+		}
+		data := make([]byte, binary.BigEndian.Uint32(lenPrefix[:]))
+		if _, err := io.ReadFull(r, data); err != nil {
+			return err
+		}
+
+		var logs []LogPayload
+		if err := json.Unmarshal(data, &logs); err != nil {
+			continue // corrupt record, skip rather than wedge the buffer
+		}
+		if err := fn(logs); err != nil {
+			return err
+		}
+	}
+
+	b.mu.Lock()
+	size := int64(0)
+	if info, err := os.Stat(path); err == nil {
+		size = info.Size()
+	}
+	if err := os.Remove(path); err == nil {
+		b.diskSize -= size
+	}
+	b.mu.Unlock()
+	return nil
+}
+
+// Close flushes and closes the active spool file.
+func (b *PersistentBuffer) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.curFile == nil {
+		return nil
+	}
+	err := b.curFile.Close()
+	b.curFile = nil
+	return err
+}
+
+// BufferStats reports queue/disk backpressure counters so operators can
+// alert on it. See Logger.Stats.
+type BufferStats struct {
+	Queued    int64
+	InFlight  int64
+	Dropped   int64
+	Persisted int64
+}
+
+// loggerStats holds the atomic counters backing Logger.Stats. Queued is
+// derived directly from len(logQueue) rather than tracked here.
+type loggerStats struct {
+	inFlight  int64
+	dropped   int64
+	persisted int64
+}
+
+func (s *loggerStats) addInFlight(n int64)  { atomic.AddInt64(&s.inFlight, n) }
+func (s *loggerStats) addDropped(n int64)   { atomic.AddInt64(&s.dropped, n) }
+func (s *loggerStats) addPersisted(n int64) { atomic.AddInt64(&s.persisted, n) }
+
+func (s *loggerStats) loadInFlight() int64  { return atomic.LoadInt64(&s.inFlight) }
+func (s *loggerStats) loadDropped() int64   { return atomic.LoadInt64(&s.dropped) }
+func (s *loggerStats) loadPersisted() int64 { return atomic.LoadInt64(&s.persisted) }
+
+// sender drains a Logger's PersistentBuffer with exponential backoff + jitter,
+// honoring Retry-After on 429/503.
+type sender struct {
+	logger *Logger
+	buffer *PersistentBuffer
+
+	maxRetries int
+	baseDelay  time.Duration
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+func newSender(l *Logger, buf *PersistentBuffer) *sender {
+	maxRetries := l.config.MaxRetries
+	if maxRetries <= 0 {
+		maxRetries = 10
+	}
+	baseDelay := l.config.RetryBaseDelay
+	if baseDelay <= 0 {
+		baseDelay = 1 * time.Second
+	}
+
+	return &sender{
+		logger:     l,
+		buffer:     buf,
+		maxRetries: maxRetries,
+		baseDelay:  baseDelay,
+		stop:       make(chan struct{}),
+	}
+}
+
+const senderMaxDelay = 30 * time.Second
+
+func (s *sender) run(interval time.Duration) {
+	s.wg.Add(1)
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.drainOnce()
+		case <-s.stop:
+			s.drainOnce()
+			return
+		}
+	}
+}
+
+func (s *sender) drainOnce() {
+	_ = s.buffer.Drain(func(logs []LogPayload) error {
+		return s.sendWithRetry(logs)
+	})
+}
+
+// sendWithRetry attempts to deliver a spooled batch, retrying with
+// exponential backoff + jitter (honoring Retry-After on 429/503) up to
+// maxRetries times before giving up and leaving the batch on disk for the
+// next drain pass. It dispatches to the single-attempt primitive matching
+// LoggerConfig.Transport, since a batch spooled under one transport must be
+// redelivered over that same wire protocol.
+func (s *sender) sendWithRetry(logs []LogPayload) error {
+	deliver := s.logger.deliverBatch
+	if s.logger.config.Transport == TransportOTLPHTTP {
+		deliver = s.logger.deliverBatchOTLP
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < s.maxRetries; attempt++ {
+		s.logger.stats.addInFlight(1)
+		retryAfter, err := deliver(logs)
+		s.logger.stats.addInFlight(-1)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+
+		delay := retryAfter
+		if delay <= 0 {
+			delay = backoffWithJitter(attempt, s.baseDelay, senderMaxDelay)
+		}
+		select {
+		case <-time.After(delay):
+		case <-s.stop:
+			return lastErr
+		}
+	}
+	return lastErr
+}
+
+func (s *sender) Stop() {
+	close(s.stop)
+	s.wg.Wait()
+}
+
@@ -0,0 +1,79 @@
+package healops
+
+import (
+	"context"
+	"runtime"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// InfoCtx logs an informational message, enriching it with trace context
+// from ctx when a span is active.
+func (l *Logger) InfoCtx(ctx context.Context, message string, metadata map[string]interface{}) {
+	l.logCtx(ctx, InfoLevel, message, metadata)
+}
+
+// WarnCtx logs a warning message, enriching it with trace context from ctx
+// when a span is active.
+func (l *Logger) WarnCtx(ctx context.Context, message string, metadata map[string]interface{}) {
+	l.logCtx(ctx, WarningLevel, message, metadata)
+}
+
+// ErrorCtx logs an error message, enriching it with trace context from ctx
+// when a span is active.
+func (l *Logger) ErrorCtx(ctx context.Context, message string, metadata map[string]interface{}) {
+	l.logCtx(ctx, ErrorLevel, message, metadata)
+}
+
+// CriticalCtx logs a critical message, enriching it with trace context from
+// ctx when a span is active.
+func (l *Logger) CriticalCtx(ctx context.Context, message string, metadata map[string]interface{}) {
+	l.logCtx(ctx, CriticalLevel, message, metadata)
+}
+
+// logCtx attaches trace_id/span_id/trace_flags from an active span in ctx
+// (if any) and caller info, then delegates to log.
+func (l *Logger) logCtx(ctx context.Context, severity LogLevel, message string, metadata map[string]interface{}) {
+	if metadata == nil {
+		metadata = make(map[string]interface{})
+	}
+
+	if ctx != nil {
+		if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+			metadata["trace_id"] = sc.TraceID().String()
+			metadata["span_id"] = sc.SpanID().String()
+			metadata["trace_flags"] = sc.TraceFlags().String()
+		}
+	}
+
+	l.logWithSkip(severity, message, metadata, callerSkip)
+}
+
+// callerSkip is the runtime.Caller depth, counted from inside
+// enrichCallerInfo, needed to land on the application frame that ultimately
+// called Info/Warn/Error/Critical or their *Ctx variants. Both paths go
+// through the same number of SDK frames (Info -> log -> logWithSkip ->
+// enrichCallerInfo, or InfoCtx -> logCtx -> logWithSkip -> enrichCallerInfo)
+// so a single constant covers both.
+const callerSkip = 4
+
+// enrichCallerInfo populates code.file.path/code.line.number/code.function
+// on metadata from the runtime call stack, skipping the SDK's own frames.
+// It reuses the same attribute keys HealOpsExporter.extractCodeInfo uses so
+// logs and traces join on the backend. Existing values are never overwritten.
+func enrichCallerInfo(metadata map[string]interface{}, skip int) {
+	if _, ok := metadata["code.file.path"]; ok {
+		return
+	}
+
+	pc, file, line, ok := runtime.Caller(skip)
+	if !ok {
+		return
+	}
+
+	metadata["code.file.path"] = file
+	metadata["code.line.number"] = line
+	if fn := runtime.FuncForPC(pc); fn != nil {
+		metadata["code.function"] = fn.Name()
+	}
+}
@@ -2,6 +2,7 @@ package healops
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -10,35 +11,145 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"go.opentelemetry.io/otel/sdk/trace"
 )
 
-// HealOpsExporter implements trace.SpanExporter
+// exporterQueueCapacity bounds the number of span batches buffered between
+// ExportSpans and the network, so a slow/unreachable backend backpressures
+// instead of blocking the batch processor.
+const exporterQueueCapacity = 256
+
+const (
+	exporterMaxRetries     = 5
+	exporterRetryBaseDelay = 500 * time.Millisecond
+	exporterRetryMaxDelay  = 30 * time.Second
+)
+
+// HealOpsExporter implements trace.SpanExporter. It speaks HealOps's bespoke
+// JSON format over HTTP by default; see NewHealOpsGRPCExporter for the
+// standard OTLP/gRPC alternative.
 type HealOpsExporter struct {
 	apiKey      string
 	serviceName string
 	endpoint    string
 	client      *http.Client
+	compress    bool
+
+	grpc *grpcTransport // nil unless created via NewHealOpsGRPCExporter
+
+	queue chan []trace.ReadOnlySpan
+	stop  chan struct{}
+	wg    sync.WaitGroup
 }
 
-// NewHealOpsExporter creates a new HealOpsExporter
+// NewHealOpsExporter creates a new HealOpsExporter that POSTs HealOps's
+// bespoke JSON span format over HTTP.
 func NewHealOpsExporter(apiKey string, serviceName string, endpoint string) *HealOpsExporter {
-	return &HealOpsExporter{
+	e := &HealOpsExporter{
 		apiKey:      apiKey,
 		serviceName: serviceName,
 		endpoint:    endpoint,
 		client:      &http.Client{Timeout: 10 * time.Second},
+		queue:       make(chan []trace.ReadOnlySpan, exporterQueueCapacity),
+		stop:        make(chan struct{}),
 	}
+	e.wg.Add(1)
+	go e.runWorker()
+	return e
 }
 
-// ExportSpans exports a batch of spans
+// WithCompression enables gzip compression of request bodies (HTTP and
+// gRPC) and returns e for chaining.
+func (e *HealOpsExporter) WithCompression() *HealOpsExporter {
+	e.compress = true
+	return e
+}
+
+// ExportSpans hands spans off to the exporter's bounded internal queue and
+// returns immediately; delivery (with retry) happens on a background
+// goroutine so a slow network doesn't block the batch span processor. An
+// error is returned if the queue is already full.
 func (e *HealOpsExporter) ExportSpans(ctx context.Context, spans []trace.ReadOnlySpan) error {
 	if len(spans) == 0 {
 		return nil
 	}
 
+	select {
+	case e.queue <- spans:
+		return nil
+	default:
+		return fmt.Errorf("healops: export queue full, dropping %d spans", len(spans))
+	}
+}
+
+func (e *HealOpsExporter) runWorker() {
+	defer e.wg.Done()
+	for {
+		select {
+		case spans := <-e.queue:
+			e.deliverWithRetry(spans)
+		case <-e.stop:
+			e.drainQueue()
+			return
+		}
+	}
+}
+
+func (e *HealOpsExporter) drainQueue() {
+	for {
+		select {
+		case spans := <-e.queue:
+			e.deliverWithRetry(spans)
+		default:
+			return
+		}
+	}
+}
+
+// deliverWithRetry delivers spans via gRPC or HTTP (whichever this exporter
+// was constructed for), retrying every failed attempt, up to
+// exporterMaxRetries, with exponential backoff + jitter. A server-suggested
+// delay replaces the backoff when present: Retry-After on HTTP 429/5xx,
+// RetryInfo on gRPC UNAVAILABLE/RESOURCE_EXHAUSTED. Failures with other HTTP
+// statuses or gRPC codes are not told apart and are retried the same way.
+func (e *HealOpsExporter) deliverWithRetry(spans []trace.ReadOnlySpan) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var lastErr error
+	for attempt := 0; attempt < exporterMaxRetries; attempt++ {
+		var retryAfter time.Duration
+		var err error
+		if e.grpc != nil {
+			retryAfter, err = e.grpc.export(ctx, e.apiKey, e.serviceName, spans, e.compress)
+		} else {
+			retryAfter, err = e.deliverHTTP(ctx, spans)
+		}
+		if err == nil {
+			return
+		}
+		lastErr = err
+
+		delay := retryAfter
+		if delay <= 0 {
+			delay = backoffWithJitter(attempt, exporterRetryBaseDelay, exporterRetryMaxDelay)
+		}
+		if !sleepWithContext(ctx, delay) {
+			break
+		}
+	}
+
+	if os.Getenv("HEALOPS_DEBUG") != "" {
+		fmt.Printf("Failed to export spans after retries: %v\n", lastErr)
+	}
+}
+
+// deliverHTTP makes a single delivery attempt against HealOps's bespoke JSON
+// endpoint, returning a Retry-After delay (if any) and error.
+func (e *HealOpsExporter) deliverHTTP(ctx context.Context, spans []trace.ReadOnlySpan) (time.Duration, error) {
 	payload := map[string]interface{}{
 		"apiKey":      e.apiKey,
 		"serviceName": e.serviceName,
@@ -47,36 +158,65 @@ func (e *HealOpsExporter) ExportSpans(ctx context.Context, spans []trace.ReadOnl
 
 	jsonData, err := json.Marshal(payload)
 	if err != nil {
-		return err
+		return 0, err
+	}
+
+	body := jsonData
+	if e.compress {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		if _, err := gz.Write(jsonData); err == nil && gz.Close() == nil {
+			body = buf.Bytes()
+		}
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", e.endpoint, bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(ctx, "POST", e.endpoint, bytes.NewReader(body))
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("User-Agent", "HealOps-OTel-Go-SDK/1.0")
+	if e.compress {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
 
 	resp, err := e.client.Do(req)
 	if err != nil {
-		if os.Getenv("HEALOPS_DEBUG") != "" {
-			fmt.Printf("Failed to export spans: %v\n", err)
-		}
-		return err
+		return 0, err
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == 429 || resp.StatusCode >= 500 {
+		retryAfter, _ := retryAfterDelay(resp.Header.Get("Retry-After"))
+		return retryAfter, fmt.Errorf("failed to export spans, status: %s", resp.Status)
+	}
 	if resp.StatusCode >= 400 {
-		return fmt.Errorf("failed to export spans, status: %s", resp.Status)
+		return 0, fmt.Errorf("failed to export spans, status: %s", resp.Status)
 	}
 
-	return nil
+	return 0, nil
 }
 
-// Shutdown shuts down the exporter
+// Shutdown drains any queued batches, giving up once ctx's deadline passes.
 func (e *HealOpsExporter) Shutdown(ctx context.Context) error {
-	return nil
+	close(e.stop)
+
+	done := make(chan struct{})
+	go func() {
+		e.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		if e.grpc != nil {
+			return e.grpc.close()
+		}
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
 func (e *HealOpsExporter) transformSpans(spans []trace.ReadOnlySpan) []map[string]interface{} {
@@ -134,6 +274,16 @@ func (e *HealOpsExporter) transformSpans(spans []trace.ReadOnlySpan) []map[strin
 var fileLineRegex = regexp.MustCompile(`\s+([^\s]+:\d+)`)
 
 func (e *HealOpsExporter) extractCodeInfo(stackTrace string, attributes map[string]interface{}) {
+	ExtractCodeInfo(stackTrace, attributes)
+}
+
+// ExtractCodeInfo parses a Go stack trace (as produced by runtime/debug.Stack
+// or a recovered panic) and populates code.file.path/code.line.number on
+// attributes with the first non-runtime frame found. Existing values are
+// never overwritten. It is exported so other HealOps sub-packages (e.g.
+// healopshttp's panic recovery) can emit attributes compatible with what
+// HealOpsExporter.extractCodeInfo derives from exception events.
+func ExtractCodeInfo(stackTrace string, attributes map[string]interface{}) {
 	// If code info is already present, don't overwrite
 	if _, ok := attributes["code.file.path"]; ok {
 		return
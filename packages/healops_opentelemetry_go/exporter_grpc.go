@@ -0,0 +1,197 @@
+package healops
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace"
+	oteltrace "go.opentelemetry.io/otel/trace"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	_ "google.golang.org/grpc/encoding/gzip" // registers the "gzip" compressor
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// grpcTransport calls the standard OTLP TraceService/Export RPC.
+type grpcTransport struct {
+	conn   *grpc.ClientConn
+	client coltracepb.TraceServiceClient
+}
+
+func newGRPCTransport(endpoint string) (*grpcTransport, error) {
+	conn, err := grpc.Dial(endpoint, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("healops: dialing OTLP/gRPC endpoint: %w", err)
+	}
+	return &grpcTransport{conn: conn, client: coltracepb.NewTraceServiceClient(conn)}, nil
+}
+
+// export makes a single Export RPC attempt, returning a retry delay (from
+// the RetryInfo detail, if present) and error for UNAVAILABLE/
+// RESOURCE_EXHAUSTED so the caller's retry loop can back off accordingly.
+func (t *grpcTransport) export(ctx context.Context, apiKey, serviceName string, spans []trace.ReadOnlySpan, compress bool) (time.Duration, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-healops-key", apiKey)
+
+	req := &coltracepb.ExportTraceServiceRequest{
+		ResourceSpans: []*tracepb.ResourceSpans{buildResourceSpans(serviceName, spans)},
+	}
+
+	var opts []grpc.CallOption
+	if compress {
+		opts = append(opts, grpc.UseCompressor("gzip"))
+	}
+
+	if _, err := t.client.Export(ctx, req, opts...); err != nil {
+		st := status.Convert(err)
+		switch st.Code() {
+		case codes.Unavailable, codes.ResourceExhausted:
+			return retryInfoDelay(st), err
+		}
+		return 0, err
+	}
+
+	return 0, nil
+}
+
+func (t *grpcTransport) close() error {
+	return t.conn.Close()
+}
+
+// retryInfoDelay extracts the server-suggested retry delay from a gRPC
+// status's google.rpc.RetryInfo detail, if present.
+func retryInfoDelay(st *status.Status) time.Duration {
+	for _, d := range st.Details() {
+		if ri, ok := d.(*errdetails.RetryInfo); ok && ri.GetRetryDelay() != nil {
+			return ri.GetRetryDelay().AsDuration()
+		}
+	}
+	return 0
+}
+
+// buildResourceSpans converts a batch of SDK spans into a single OTLP
+// ResourceSpans, tagged with service.name.
+func buildResourceSpans(serviceName string, spans []trace.ReadOnlySpan) *tracepb.ResourceSpans {
+	protoSpans := make([]*tracepb.Span, 0, len(spans))
+	for _, s := range spans {
+		protoSpans = append(protoSpans, toOTLPSpan(s))
+	}
+
+	return &tracepb.ResourceSpans{
+		Resource: &resourcepb.Resource{
+			Attributes: []*commonpb.KeyValue{
+				{Key: "service.name", Value: toAnyValue(serviceName)},
+			},
+		},
+		ScopeSpans: []*tracepb.ScopeSpans{
+			{Spans: protoSpans},
+		},
+	}
+}
+
+func toOTLPSpan(s trace.ReadOnlySpan) *tracepb.Span {
+	sc := s.SpanContext()
+	traceID := sc.TraceID()
+	spanID := sc.SpanID()
+
+	attrs := make([]*commonpb.KeyValue, 0, len(s.Attributes()))
+	for _, kv := range s.Attributes() {
+		attrs = append(attrs, &commonpb.KeyValue{Key: string(kv.Key), Value: toAnyValue(kv.Value.AsInterface())})
+	}
+
+	events := make([]*tracepb.Span_Event, 0, len(s.Events()))
+	for _, ev := range s.Events() {
+		evAttrs := make([]*commonpb.KeyValue, 0, len(ev.Attributes))
+		for _, kv := range ev.Attributes {
+			evAttrs = append(evAttrs, &commonpb.KeyValue{Key: string(kv.Key), Value: toAnyValue(kv.Value.AsInterface())})
+		}
+		events = append(events, &tracepb.Span_Event{
+			TimeUnixNano: uint64(ev.Time.UnixNano()),
+			Name:         ev.Name,
+			Attributes:   evAttrs,
+		})
+	}
+
+	span := &tracepb.Span{
+		TraceId:           traceID[:],
+		SpanId:            spanID[:],
+		Name:              s.Name(),
+		Kind:              toOTLPSpanKind(s.SpanKind()),
+		StartTimeUnixNano: uint64(s.StartTime().UnixNano()),
+		EndTimeUnixNano:   uint64(s.EndTime().UnixNano()),
+		Attributes:        attrs,
+		Events:            events,
+		Status: &tracepb.Status{
+			Code:    toOTLPStatusCode(s.Status().Code),
+			Message: s.Status().Description,
+		},
+	}
+
+	if parent := s.Parent(); parent.IsValid() {
+		parentID := parent.SpanID()
+		span.ParentSpanId = parentID[:]
+	}
+
+	return span
+}
+
+func toOTLPSpanKind(kind oteltrace.SpanKind) tracepb.Span_SpanKind {
+	switch kind {
+	case oteltrace.SpanKindServer:
+		return tracepb.Span_SPAN_KIND_SERVER
+	case oteltrace.SpanKindClient:
+		return tracepb.Span_SPAN_KIND_CLIENT
+	case oteltrace.SpanKindProducer:
+		return tracepb.Span_SPAN_KIND_PRODUCER
+	case oteltrace.SpanKindConsumer:
+		return tracepb.Span_SPAN_KIND_CONSUMER
+	case oteltrace.SpanKindInternal:
+		return tracepb.Span_SPAN_KIND_INTERNAL
+	default:
+		return tracepb.Span_SPAN_KIND_UNSPECIFIED
+	}
+}
+
+func toOTLPStatusCode(code otelcodes.Code) tracepb.Status_StatusCode {
+	switch code {
+	case otelcodes.Ok:
+		return tracepb.Status_STATUS_CODE_OK
+	case otelcodes.Error:
+		return tracepb.Status_STATUS_CODE_ERROR
+	default:
+		return tracepb.Status_STATUS_CODE_UNSET
+	}
+}
+
+// NewHealOpsGRPCExporter creates a HealOpsExporter that calls the standard
+// OTLP TraceService/Export RPC over gRPC instead of posting HealOps's
+// bespoke JSON format.
+func NewHealOpsGRPCExporter(apiKey, serviceName, endpoint string) (*HealOpsExporter, error) {
+	transport, err := newGRPCTransport(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &HealOpsExporter{
+		apiKey:      apiKey,
+		serviceName: serviceName,
+		endpoint:    endpoint,
+		grpc:        transport,
+		queue:       make(chan []trace.ReadOnlySpan, exporterQueueCapacity),
+		stop:        make(chan struct{}),
+	}
+	e.wg.Add(1)
+	go e.runWorker()
+
+	return e, nil
+}
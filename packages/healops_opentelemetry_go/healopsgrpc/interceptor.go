@@ -0,0 +1,147 @@
+// Package healopsgrpc provides gRPC server interceptors that mirror
+// healopshttp's middleware: a server span per call, panic recovery into
+// CriticalLevel logs, and a structured access log via healops.Logger.
+package healopsgrpc
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+
+	healops "github.com/healops/healops-opentelemetry-go"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const tracerName = "github.com/healops/healops-opentelemetry-go/healopsgrpc"
+
+// metadataCarrier adapts incoming gRPC metadata to propagation.TextMapCarrier
+// so the global propagator can extract a W3C traceparent from it.
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	vals := metadata.MD(c).Get(key)
+	if len(vals) == 0 {
+		return ""
+	}
+	return vals[0]
+}
+
+func (c metadataCarrier) Set(key, value string) { metadata.MD(c).Set(key, value) }
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+func startSpan(ctx context.Context, method string) (context.Context, trace.Span) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		md = metadata.MD{}
+	}
+	ctx = otel.GetTextMapPropagator().Extract(ctx, metadataCarrier(md))
+	return otel.Tracer(tracerName).Start(ctx, method, trace.WithSpanKind(trace.SpanKindServer))
+}
+
+// logPanic records a recovered panic onto span and as a CriticalLevel log,
+// returning the gRPC status error the interceptor should report instead of
+// letting the panic unwind into grpc-go's own (connection-ending) recovery.
+func logPanic(ctx context.Context, logger *healops.Logger, span trace.Span, method string, rec interface{}) error {
+	stack := string(debug.Stack())
+	attrs := map[string]interface{}{
+		"rpc.method": method,
+		"panic":      fmt.Sprintf("%v", rec),
+		"stack":      stack,
+	}
+	healops.ExtractCodeInfo(stack, attrs)
+	logger.CriticalCtx(ctx, "panic recovered in gRPC handler", attrs)
+
+	span.RecordError(fmt.Errorf("panic: %v", rec))
+	span.SetStatus(otelcodes.Error, "panic recovered")
+
+	return status.Errorf(codes.Internal, "internal error")
+}
+
+// UnaryServerInterceptor returns a grpc.UnaryServerInterceptor that starts a
+// server span per call, recovers panics (logging them at CriticalLevel), and
+// emits an access log via logger.InfoCtx.
+func UnaryServerInterceptor(logger *healops.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		ctx, span := startSpan(ctx, info.FullMethod)
+		defer span.End()
+		span.SetAttributes(attribute.String("rpc.method", info.FullMethod))
+
+		defer func() {
+			if rec := recover(); rec != nil {
+				err = logPanic(ctx, logger, span, info.FullMethod, rec)
+			} else if err != nil {
+				st, _ := status.FromError(err)
+				span.RecordError(err)
+				span.SetStatus(otelcodes.Error, st.Message())
+			}
+
+			st, _ := status.FromError(err)
+			span.SetAttributes(attribute.String("rpc.grpc.status_code", st.Code().String()))
+
+			logger.InfoCtx(ctx, "grpc request", map[string]interface{}{
+				"rpc.method":           info.FullMethod,
+				"rpc.grpc.status_code": st.Code().String(),
+			})
+		}()
+
+		resp, err = handler(ctx, req)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor returns a grpc.StreamServerInterceptor with the
+// same instrumentation as UnaryServerInterceptor.
+func StreamServerInterceptor(logger *healops.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		ctx, span := startSpan(ss.Context(), info.FullMethod)
+		defer span.End()
+		span.SetAttributes(attribute.String("rpc.method", info.FullMethod))
+
+		defer func() {
+			if rec := recover(); rec != nil {
+				err = logPanic(ctx, logger, span, info.FullMethod, rec)
+			} else if err != nil {
+				st, _ := status.FromError(err)
+				span.RecordError(err)
+				span.SetStatus(otelcodes.Error, st.Message())
+			}
+
+			st, _ := status.FromError(err)
+			span.SetAttributes(attribute.String("rpc.grpc.status_code", st.Code().String()))
+
+			logger.InfoCtx(ctx, "grpc stream", map[string]interface{}{
+				"rpc.method":           info.FullMethod,
+				"rpc.grpc.status_code": st.Code().String(),
+			})
+		}()
+
+		wrapped := &wrappedServerStream{ServerStream: ss, ctx: ctx}
+		err = handler(srv, wrapped)
+		return err
+	}
+}
+
+// wrappedServerStream overrides Context so handlers observe the span-bearing
+// context created by the interceptor.
+type wrappedServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (w *wrappedServerStream) Context() context.Context { return w.ctx }
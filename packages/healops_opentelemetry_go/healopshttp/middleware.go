@@ -0,0 +1,113 @@
+// Package healopshttp provides an HTTP middleware that wraps each request in
+// a server span, recovers panics into CriticalLevel logs, and emits a
+// structured access log through a healops.Logger.
+package healopshttp
+
+import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	healops "github.com/healops/healops-opentelemetry-go"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/healops/healops-opentelemetry-go/healopshttp"
+
+// Middleware wraps handler with HealOps instrumentation: it starts a server
+// span (extracting an incoming W3C traceparent), records OTel HTTP semantic
+// convention attributes, recovers panics (logging them at CriticalLevel with
+// the goroutine stack), and emits an access log via logger.InfoCtx.
+func Middleware(logger *healops.Logger) func(http.Handler) http.Handler {
+	tracer := otel.Tracer(tracerName)
+	propagator := otel.GetTextMapPropagator()
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := propagator.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+
+			route := r.URL.Path
+			ctx, span := tracer.Start(ctx, route, trace.WithSpanKind(trace.SpanKindServer))
+			defer span.End()
+
+			span.SetAttributes(
+				attribute.String("http.method", r.Method),
+				attribute.String("http.route", route),
+			)
+
+			sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+			r = r.WithContext(ctx)
+
+			defer func() {
+				if rec := recover(); rec != nil {
+					stack := string(debug.Stack())
+
+					attrs := map[string]interface{}{
+						"http.method": r.Method,
+						"http.route":  route,
+						"panic":       fmt.Sprintf("%v", rec),
+						"stack":       stack,
+					}
+					healops.ExtractCodeInfo(stack, attrs)
+					logger.CriticalCtx(ctx, "panic recovered in HTTP handler", attrs)
+
+					span.RecordError(fmt.Errorf("panic: %v", rec))
+					span.SetStatus(codes.Error, "panic recovered")
+
+					if !sw.wroteHeader {
+						sw.WriteHeader(http.StatusInternalServerError)
+					}
+				}
+
+				span.SetAttributes(
+					attribute.Int("http.status_code", sw.status),
+					attribute.Int64("http.response.size", sw.size),
+				)
+				if sw.status >= 500 {
+					span.SetStatus(codes.Error, http.StatusText(sw.status))
+				}
+
+				logger.InfoCtx(ctx, "http request", map[string]interface{}{
+					"http.method":        r.Method,
+					"http.route":         route,
+					"http.status_code":   sw.status,
+					"http.response.size": sw.size,
+				})
+			}()
+
+			next.ServeHTTP(sw, r)
+		})
+	}
+}
+
+// statusWriter captures the status code and byte count of a response so they
+// can be recorded on the span and access log after the handler returns.
+type statusWriter struct {
+	http.ResponseWriter
+	status      int
+	size        int64
+	wroteHeader bool
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.size += int64(n)
+	return n, err
+}
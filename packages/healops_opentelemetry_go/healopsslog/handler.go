@@ -0,0 +1,107 @@
+// Package healopsslog bridges the standard library's log/slog into a
+// healops.Logger, so existing slog call sites get HealOps ingestion for free.
+package healopsslog
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+
+	healops "github.com/healops/healops-opentelemetry-go"
+)
+
+// Handler is an slog.Handler that forwards records into a healops.Logger.
+type Handler struct {
+	logger *healops.Logger
+	attrs  []slog.Attr
+	group  string
+}
+
+// New creates an slog.Handler backed by logger.
+func New(logger *healops.Logger) *Handler {
+	return &Handler{logger: logger}
+}
+
+// Enabled reports whether the handler handles records at the given level.
+// HealOps ingestion decisions are made downstream, so every level is handled.
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return true
+}
+
+// Handle forwards record to the underlying Logger, mapping slog levels onto
+// HealOps severities and flattening attributes (respecting WithGroup) into
+// LogPayload.Metadata.
+func (h *Handler) Handle(ctx context.Context, record slog.Record) error {
+	metadata := make(map[string]interface{}, record.NumAttrs()+len(h.attrs))
+	for _, a := range h.attrs {
+		metadata[a.Key] = a.Value.Any()
+	}
+	record.Attrs(func(a slog.Attr) bool {
+		key := a.Key
+		if h.group != "" {
+			key = h.group + "." + key
+		}
+		metadata[key] = a.Value.Any()
+		return true
+	})
+	populateCallerInfo(metadata, record.PC)
+
+	switch {
+	case record.Level >= slog.LevelError:
+		h.logger.ErrorCtx(ctx, record.Message, metadata)
+	case record.Level >= slog.LevelWarn:
+		h.logger.WarnCtx(ctx, record.Message, metadata)
+	default:
+		h.logger.InfoCtx(ctx, record.Message, metadata)
+	}
+	return nil
+}
+
+// WithAttrs returns a Handler that also includes attrs on every record.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	merged := make([]slog.Attr, 0, len(h.attrs)+len(attrs))
+	merged = append(merged, h.attrs...)
+	for _, a := range attrs {
+		if h.group != "" {
+			a.Key = h.group + "." + a.Key
+		}
+		merged = append(merged, a)
+	}
+	return &Handler{logger: h.logger, attrs: merged, group: h.group}
+}
+
+// WithGroup returns a Handler that prefixes subsequent attribute keys with name.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	group := name
+	if h.group != "" {
+		group = h.group + "." + name
+	}
+	return &Handler{logger: h.logger, attrs: h.attrs, group: group}
+}
+
+// populateCallerInfo fills metadata's code.file.path/code.line.number/
+// code.function from record's PC, which slog captures at the original
+// Info/Warn/Error call site. Without this, Logger's own caller-info
+// enrichment would instead land on this Handle method, since it's the
+// frame that actually calls into Logger. Uses the same keys
+// healops.enrichCallerInfo does, and is likewise a no-op if already set, so
+// the two compose cleanly.
+func populateCallerInfo(metadata map[string]interface{}, pc uintptr) {
+	if pc == 0 {
+		return
+	}
+	if _, ok := metadata["code.file.path"]; ok {
+		return
+	}
+
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	if frame.File == "" {
+		return
+	}
+
+	metadata["code.file.path"] = frame.File
+	metadata["code.line.number"] = frame.Line
+	if frame.Function != "" {
+		metadata["code.function"] = frame.Function
+	}
+}
@@ -0,0 +1,57 @@
+package healopsslog
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	healops "github.com/healops/healops-opentelemetry-go"
+)
+
+// TestHandleAttributesCallerToApplicationSite guards against caller info
+// landing on Handle's own frame (the bug a fixed stack depth in
+// healops.Logger would otherwise reproduce): it must point at the slog
+// call site in this test function, not at handler.go.
+func TestHandleAttributesCallerToApplicationSite(t *testing.T) {
+	var mu sync.Mutex
+	var gotMetadata map[string]interface{}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			Metadata map[string]interface{} `json:"metadata"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("decoding request body: %v", err)
+			return
+		}
+		mu.Lock()
+		gotMetadata = payload.Metadata
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	logger := healops.NewLogger(healops.LoggerConfig{Endpoint: srv.URL})
+	defer logger.Shutdown()
+
+	handler := New(logger)
+	slogger := slog.New(handler)
+	slogger.Info("test message") // <-- this is the call site we expect to see
+
+	mu.Lock()
+	defer mu.Unlock()
+	if gotMetadata == nil {
+		t.Fatal("server never received a log payload")
+	}
+
+	file, _ := gotMetadata["code.file.path"].(string)
+	if !strings.HasSuffix(file, "handler_test.go") {
+		t.Fatalf("got code.file.path=%q, want it to point at this test file, not the bridge", file)
+	}
+	if _, ok := gotMetadata["code.line.number"]; !ok {
+		t.Fatal("expected code.line.number to be populated")
+	}
+}
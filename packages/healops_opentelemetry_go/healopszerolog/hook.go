@@ -0,0 +1,74 @@
+// Package healopszerolog bridges github.com/rs/zerolog into a healops.Logger
+// via a zerolog.Hook, so existing zerolog call sites get HealOps ingestion
+// for free.
+package healopszerolog
+
+import (
+	"runtime"
+
+	"github.com/rs/zerolog"
+
+	healops "github.com/healops/healops-opentelemetry-go"
+)
+
+// Hook is a zerolog.Hook that forwards events into a healops.Logger.
+//
+// zerolog.Hook only exposes the event's level and message, not fields
+// already written onto it, so per-event fields added before the hook runs
+// are not forwarded; use healops.Logger directly (or its metadata map) for
+// structured fields you need on the HealOps side.
+type Hook struct {
+	Logger *healops.Logger
+}
+
+// New creates a Hook backed by logger.
+func New(logger *healops.Logger) Hook {
+	return Hook{Logger: logger}
+}
+
+// zerologCallerSkip is the runtime.Caller depth, counted from inside
+// callerMetadata, needed to land on the application frame that triggered it:
+// callerMetadata is called by Run, which is called directly by zerolog's
+// Event.msg, which is called directly by exactly one of
+// Msg/Msgf/MsgFunc/Send, which the application calls directly.
+const zerologCallerSkip = 4
+
+// Run implements zerolog.Hook.
+func (h Hook) Run(e *zerolog.Event, level zerolog.Level, message string) {
+	if h.Logger == nil || level == zerolog.NoLevel {
+		return
+	}
+
+	metadata := callerMetadata()
+
+	switch {
+	case level >= zerolog.ErrorLevel:
+		h.Logger.Error(message, metadata)
+	case level == zerolog.WarnLevel:
+		h.Logger.Warn(message, metadata)
+	default:
+		h.Logger.Info(message, metadata)
+	}
+}
+
+// callerMetadata captures the application call site that triggered the
+// zerolog event, using the same code.file.path/code.line.number/
+// code.function keys healops.enrichCallerInfo does. Populating them here
+// means Logger's own enrichment (which would otherwise land on this Run
+// method, since it's the frame that actually calls into Logger) is a
+// no-op, so the two compose cleanly.
+func callerMetadata() map[string]interface{} {
+	pc, file, line, ok := runtime.Caller(zerologCallerSkip)
+	if !ok {
+		return nil
+	}
+
+	metadata := map[string]interface{}{
+		"code.file.path":   file,
+		"code.line.number": line,
+	}
+	if fn := runtime.FuncForPC(pc); fn != nil {
+		metadata["code.function"] = fn.Name()
+	}
+	return metadata
+}
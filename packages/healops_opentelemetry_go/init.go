@@ -12,10 +12,40 @@ type Config struct {
 	ServiceName    string
 	Endpoint       string
 	CaptureConsole bool // Note: Go doesn't have console interception like Node/Python easily, so this might just be a flag
-	CaptureErrors  bool // Go doesn't have global exception handler, but we can provide panic recovery middleware
 	CaptureTraces  bool
 	Debug          bool
 	Environment    string
+
+	// CaptureErrors enables automatic panic/error capture for HTTP and gRPC
+	// servers. healops can't import healopshttp/healopsgrpc itself (they
+	// both import healops, so that would be a cycle), so Init can't wire the
+	// middleware/interceptor on its own; instead, when CaptureErrors is true,
+	// Init calls RegisterErrorCapture with the constructed Logger so the
+	// caller can register healopshttp.Middleware / healopsgrpc's
+	// interceptors on its own mux or grpc.Server.
+	CaptureErrors bool
+	// RegisterErrorCapture is invoked by Init with the constructed Logger
+	// when CaptureErrors is true. It is the caller's extension point for
+	// wiring healopshttp.Middleware and/or healopsgrpc.UnaryServerInterceptor
+	// / StreamServerInterceptor onto its own mux or grpc.Server. Ignored if
+	// CaptureErrors is false or this is nil.
+	RegisterErrorCapture func(*Logger)
+
+	// TracesSampler selects the sampling strategy: "always" (default),
+	// "never", "ratio", "parentbased_ratio", or "tail_error". "ratio" and
+	// "parentbased_ratio" sample TracesSampleRate of traces up front;
+	// "tail_error" buffers each trace until its root span ends and always
+	// keeps traces containing an error, otherwise keeping TracesSampleRate of
+	// the rest (see TailSampler).
+	TracesSampler string
+	// TracesSampleRate is the sampling probability (0..1) used by the
+	// "ratio", "parentbased_ratio", and "tail_error" samplers.
+	TracesSampleRate float64
+
+	// TracesTransport selects how spans are shipped: "http-json" (default)
+	// for HealOps's bespoke JSON over HTTP, "http-proto" for standard
+	// OTLP/HTTP+protobuf, or "grpc" for standard OTLP/gRPC.
+	TracesTransport string
 }
 
 // Init initializes the HealOps SDK
@@ -33,11 +63,15 @@ func Init(config Config) (*Logger, func(), error) {
 		EnableBatching: true,
 	})
 
+	if config.CaptureErrors && config.RegisterErrorCapture != nil {
+		config.RegisterErrorCapture(logger)
+	}
+
 	var shutdownTracer func(context.Context) error
 
 	if config.CaptureTraces {
 		var err error
-		shutdownTracer, err = InitTracer(context.Background(), config.APIKey, config.ServiceName, config.Endpoint)
+		shutdownTracer, err = InitTracer(context.Background(), config)
 		if err != nil {
 			if config.Debug {
 				fmt.Printf("Failed to initialize OpenTelemetry: %v\n", err)
@@ -13,24 +13,20 @@ import (
 	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
 )
 
-// InitTracer initializes the OpenTelemetry tracer
-func InitTracer(ctx context.Context, apiKey string, serviceName string, endpoint string) (func(context.Context) error, error) {
+// InitTracer initializes the OpenTelemetry tracer using the sampling
+// strategy selected by config.TracesSampler/TracesSampleRate.
+func InitTracer(ctx context.Context, config Config) (func(context.Context) error, error) {
+	apiKey := config.APIKey
+	serviceName := config.ServiceName
+	endpoint := config.Endpoint
 	if endpoint == "" {
 		endpoint = "https://engine.healops.ai/otel/errors"
 	}
 
-    // Note: The standard OTLP exporter sends protobuf by default.
-    // HealOps custom exporter in Node/Python uses a custom JSON format.
-    // For Go, to match perfectly, we would implement a custom SpanExporter interface.
-    // For now, we use standard OTLP HTTP which many backends support,
-    // but if HealOps backend strictly requires the custom JSON format from Node/Python SDKs,
-    // we would need to implement `sdktrace.SpanExporter`.
-
-    // Assuming we want to use the standard OTLP exporter for now, or we can implement a custom one.
-    // Given the Python/Node implementation uses a custom payload structure, let's implement a custom exporter.
-
-    // Using custom exporter
-    exporter := NewHealOpsExporter(apiKey, serviceName, endpoint)
+	exporter, err := newConfiguredExporter(ctx, config.TracesTransport, apiKey, serviceName, endpoint)
+	if err != nil {
+		return nil, err
+	}
 
 	res, err := resource.New(ctx,
 		resource.WithAttributes(
@@ -41,9 +37,20 @@ func InitTracer(ctx context.Context, apiKey string, serviceName string, endpoint
 		return nil, fmt.Errorf("failed to create resource: %w", err)
 	}
 
+	if config.TracesSampler == "tail_error" {
+		tailSampler := NewTailSampler(exporter, config.TracesSampleRate, 0, 30*time.Second)
+		tracerProvider := sdktrace.NewTracerProvider(
+			sdktrace.WithSampler(sdktrace.AlwaysSample()),
+			sdktrace.WithResource(res),
+			sdktrace.WithSpanProcessor(tailSampler),
+		)
+		otel.SetTracerProvider(tracerProvider)
+		return tracerProvider.Shutdown, nil
+	}
+
 	bsp := sdktrace.NewBatchSpanProcessor(exporter, sdktrace.WithBatchTimeout(5*time.Second))
 	tracerProvider := sdktrace.NewTracerProvider(
-		sdktrace.WithSampler(sdktrace.AlwaysSample()),
+		sdktrace.WithSampler(headSampler(config.TracesSampler, config.TracesSampleRate)),
 		sdktrace.WithResource(res),
 		sdktrace.WithSpanProcessor(bsp),
 	)
@@ -52,11 +59,46 @@ func InitTracer(ctx context.Context, apiKey string, serviceName string, endpoint
 	return tracerProvider.Shutdown, nil
 }
 
-// Standard OTLP fallback (if needed)
+// headSampler maps Config.TracesSampler onto a head (start-of-trace) sampler.
+// "tail_error" is handled separately in InitTracer via TailSampler and never
+// reaches here.
+func headSampler(strategy string, sampleRate float64) sdktrace.Sampler {
+	switch strategy {
+	case "never":
+		return sdktrace.NeverSample()
+	case "ratio":
+		return sdktrace.TraceIDRatioBased(sampleRate)
+	case "parentbased_ratio":
+		return sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRate))
+	case "always", "":
+		fallthrough
+	default:
+		return sdktrace.AlwaysSample()
+	}
+}
+
+// newConfiguredExporter picks the span exporter for config.TracesTransport:
+// HealOps's bespoke JSON over HTTP (the default), standard OTLP/HTTP+proto,
+// or standard OTLP/gRPC.
+func newConfiguredExporter(ctx context.Context, transport, apiKey, serviceName, endpoint string) (sdktrace.SpanExporter, error) {
+	switch transport {
+	case "grpc":
+		return NewHealOpsGRPCExporter(apiKey, serviceName, endpoint)
+	case "http-proto":
+		return initStandardOTLP(ctx, endpoint)
+	case "http-json", "":
+		return NewHealOpsExporter(apiKey, serviceName, endpoint), nil
+	default:
+		return nil, fmt.Errorf("healops: unknown TracesTransport %q", transport)
+	}
+}
+
+// initStandardOTLP sends spans via standard OTLP/HTTP+protobuf instead of
+// HealOps's bespoke JSON format.
 func initStandardOTLP(ctx context.Context, endpoint string) (*otlptrace.Exporter, error) {
-    client := otlptracehttp.NewClient(
-        otlptracehttp.WithEndpoint(endpoint),
-        otlptracehttp.WithInsecure(), // If needed
-    )
-    return otlptrace.New(ctx, client)
+	client := otlptracehttp.NewClient(
+		otlptracehttp.WithEndpoint(endpoint),
+		otlptracehttp.WithInsecure(), // If needed
+	)
+	return otlptrace.New(ctx, client)
 }
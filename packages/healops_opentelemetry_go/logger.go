@@ -22,15 +22,39 @@ const (
 
 // LoggerConfig Configuration for HealOps Logger
 type LoggerConfig struct {
-	APIKey          string
-	ServiceName     string
-	Endpoint        string
-	Source          string
-	Environment     string
-	Release         string
-	EnableBatching  bool
-	BatchSize       int
-	BatchInterval   time.Duration
+	APIKey         string
+	ServiceName    string
+	Endpoint       string
+	Source         string
+	Environment    string
+	Release        string
+	EnableBatching bool
+	BatchSize      int
+	BatchInterval  time.Duration
+
+	// Transport selects the wire protocol used to ship batches. Defaults to
+	// TransportHealOpsJSON. Set to TransportOTLPHTTP to ship logs as OTLP
+	// ExportLogsServiceRequest protobufs instead.
+	Transport Transport
+	// OTLPEndpoint overrides the derived "<Endpoint>/v1/logs" URL used by the
+	// OTLP/HTTP transport.
+	OTLPEndpoint string
+	// OTLPCompress gzip-compresses OTLP request bodies when true.
+	OTLPCompress bool
+
+	// DiskBufferDir, when set, enables a PersistentBuffer: batches that
+	// overflow logQueue or fail to send are spooled here instead of dropped,
+	// and a background sender drains them with retry + backoff.
+	DiskBufferDir string
+	// MaxDiskBytes caps the total size of DiskBufferDir; oldest spooled
+	// batches are evicted first once the cap is hit. <= 0 means unbounded.
+	MaxDiskBytes int64
+	// MaxRetries bounds the sender's retry attempts per spooled batch.
+	// Defaults to 10.
+	MaxRetries int
+	// RetryBaseDelay is the base of the sender's exponential backoff.
+	// Defaults to 1s; capped at 30s.
+	RetryBaseDelay time.Duration
 }
 
 // LogPayload represents a log message sent to the backend
@@ -52,6 +76,10 @@ type Logger struct {
 	done      chan struct{}
 	wg        sync.WaitGroup
 	isRunning bool
+
+	buffer *PersistentBuffer
+	sender *sender
+	stats  loggerStats
 }
 
 // NewLogger creates a new HealOps Logger
@@ -68,6 +96,9 @@ func NewLogger(config LoggerConfig) *Logger {
 	if config.BatchInterval <= 0 {
 		config.BatchInterval = 1 * time.Second
 	}
+	if config.Transport == "" {
+		config.Transport = TransportHealOpsJSON
+	}
 
 	logger := &Logger{
 		config:    config,
@@ -76,6 +107,19 @@ func NewLogger(config LoggerConfig) *Logger {
 		isRunning: true,
 	}
 
+	if config.DiskBufferDir != "" {
+		buf, err := NewPersistentBuffer(config.DiskBufferDir, config.MaxDiskBytes)
+		if err != nil {
+			if os.Getenv("HEALOPS_DEBUG") != "" {
+				fmt.Printf("HealOps: disk buffer disabled: %v\n", err)
+			}
+		} else {
+			logger.buffer = buf
+			logger.sender = newSender(logger, buf)
+			go logger.sender.run(config.BatchInterval)
+		}
+	}
+
 	if config.EnableBatching {
 		logger.wg.Add(1)
 		go logger.processBatch()
@@ -84,6 +128,17 @@ func NewLogger(config LoggerConfig) *Logger {
 	return logger
 }
 
+// Stats reports the Logger's current backpressure counters so operators can
+// alert on queueing/drops without scraping logs.
+func (l *Logger) Stats() BufferStats {
+	return BufferStats{
+		Queued:    int64(len(l.logQueue)),
+		InFlight:  l.stats.loadInFlight(),
+		Dropped:   l.stats.loadDropped(),
+		Persisted: l.stats.loadPersisted(),
+	}
+}
+
 // Info logs an informational message
 func (l *Logger) Info(message string, metadata map[string]interface{}) {
 	l.log(InfoLevel, message, metadata)
@@ -115,18 +170,31 @@ func (l *Logger) Shutdown() {
 
 	// Flush remaining logs in queue
     // Note: In a real implementation, we would want to ensure channel is drained
+
+	if l.sender != nil {
+		l.sender.Stop()
+	}
+	if l.buffer != nil {
+		l.buffer.Close()
+	}
 }
 
 func (l *Logger) log(severity LogLevel, message string, metadata map[string]interface{}) {
+	if metadata == nil {
+		metadata = make(map[string]interface{})
+	}
+	l.logWithSkip(severity, message, metadata, callerSkip)
+}
+
+// logWithSkip is the common path for log/logCtx; skip is the runtime.Caller
+// depth needed to land on the application frame that ultimately called Info/
+// Warn/Error/Critical (or their *Ctx variants).
+func (l *Logger) logWithSkip(severity LogLevel, message string, metadata map[string]interface{}, skip int) {
 	if !l.isRunning {
 		return
 	}
 
-    // Enrich metadata with caller info (simplified for now)
-    // In a full implementation, we would use runtime.Caller here
-    if metadata == nil {
-        metadata = make(map[string]interface{})
-    }
+	enrichCallerInfo(metadata, skip)
 
 	payload := LogPayload{
 		ServiceName: l.config.ServiceName,
@@ -143,7 +211,15 @@ func (l *Logger) log(severity LogLevel, message string, metadata map[string]inte
 		select {
 		case l.logQueue <- payload:
 		default:
-			// Queue full, drop log or send directly (fallback)
+			// Queue full: spool to the persistent buffer instead of
+			// dropping, if one is configured.
+			if l.buffer != nil {
+				if err := l.buffer.Persist([]LogPayload{payload}); err == nil {
+					l.stats.addPersisted(1)
+					return
+				}
+			}
+			l.stats.addDropped(1)
 			if os.Getenv("HEALOPS_DEBUG") != "" {
 				fmt.Println("HealOps log queue full, dropping log")
 			}
@@ -190,19 +266,52 @@ func (l *Logger) processBatch() {
 }
 
 func (l *Logger) sendBatch(logs []LogPayload) {
-	url := fmt.Sprintf("%s/ingest/logs/batch", l.config.Endpoint)
+	if l.config.Transport == TransportOTLPHTTP {
+		l.sendBatchOTLP(logs)
+		return
+	}
 
-    jsonData, err := json.Marshal(map[string]interface{}{"logs": logs})
+	l.stats.addInFlight(1)
+	_, err := l.deliverBatch(logs)
+	l.stats.addInFlight(-1)
 	if err != nil {
 		if os.Getenv("HEALOPS_DEBUG") != "" {
-			fmt.Printf("Error marshalling batch logs: %v\n", err)
+			fmt.Printf("Error sending batch logs: %v\n", err)
+		}
+		if l.buffer != nil {
+			if perr := l.buffer.Persist(logs); perr == nil {
+				l.stats.addPersisted(int64(len(logs)))
+				return
+			}
+		}
+		// No disk buffer (or spooling failed): fall back to best-effort
+		// per-log sends rather than lose the whole batch.
+		for _, log := range logs {
+			l.sendSingleLog(log)
 		}
 		return
 	}
 
+    if os.Getenv("HEALOPS_DEBUG") != "" {
+        fmt.Printf("HealOps flushed %d logs\n", len(logs))
+    }
+}
+
+// deliverBatch makes a single delivery attempt for logs against the bespoke
+// HealOps batch endpoint, returning a Retry-After delay (if any) and error.
+// sendBatch calls it once (a failed batch is then spooled or sent log by
+// log); the sender's disk-backed retry loop is what retries it.
+func (l *Logger) deliverBatch(logs []LogPayload) (time.Duration, error) {
+	url := fmt.Sprintf("%s/ingest/logs/batch", l.config.Endpoint)
+
+	jsonData, err := json.Marshal(map[string]interface{}{"logs": logs})
+	if err != nil {
+		return 0, err
+	}
+
 	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
 	if err != nil {
-		return
+		return 0, err
 	}
 
 	req.Header.Set("Content-Type", "application/json")
@@ -211,23 +320,29 @@ func (l *Logger) sendBatch(logs []LogPayload) {
 	client := &http.Client{Timeout: 5 * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
-		if os.Getenv("HEALOPS_DEBUG") != "" {
-			fmt.Printf("Error sending batch logs: %v\n", err)
-		}
-		// Fallback to single logs?
-        for _, log := range logs {
-            l.sendSingleLog(log)
-        }
-		return
+		return 0, err
 	}
 	defer resp.Body.Close()
 
-    if os.Getenv("HEALOPS_DEBUG") != "" {
-        fmt.Printf("HealOps flushed %d logs\n", len(logs))
-    }
+	if resp.StatusCode >= 300 {
+		var retryAfter time.Duration
+		if resp.StatusCode == 429 || resp.StatusCode == 503 {
+			if d, ok := retryAfterDelay(resp.Header.Get("Retry-After")); ok {
+				retryAfter = d
+			}
+		}
+		return retryAfter, fmt.Errorf("healops: log delivery failed, status: %s", resp.Status)
+	}
+
+	return 0, nil
 }
 
 func (l *Logger) sendSingleLog(payload LogPayload) {
+	if l.config.Transport == TransportOTLPHTTP {
+		l.sendBatchOTLP([]LogPayload{payload})
+		return
+	}
+
 	url := fmt.Sprintf("%s/ingest/logs", l.config.Endpoint)
 	jsonData, err := json.Marshal(payload)
 	if err != nil {
@@ -248,6 +363,15 @@ func (l *Logger) sendSingleLog(payload LogPayload) {
 		if os.Getenv("HEALOPS_DEBUG") != "" {
 			fmt.Printf("Error sending log: %v\n", err)
 		}
+		if l.buffer != nil {
+			if perr := l.buffer.Persist([]LogPayload{payload}); perr == nil {
+				l.stats.addPersisted(1)
+			} else {
+				l.stats.addDropped(1)
+			}
+		} else {
+			l.stats.addDropped(1)
+		}
 		return
 	}
 	defer resp.Body.Close()
@@ -0,0 +1,327 @@
+package healops
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+)
+
+// Transport selects the wire protocol the Logger uses to ship log batches.
+type Transport string
+
+const (
+	// TransportHealOpsJSON is the original bespoke HealOps JSON transport
+	// (POST /ingest/logs[/batch] with an X-HealOps-Key header). This is the default.
+	TransportHealOpsJSON Transport = "healops_json"
+	// TransportOTLPHTTP ships batches as OTLP ExportLogsServiceRequest protobufs
+	// over HTTP, mirroring otlploghttp.
+	TransportOTLPHTTP Transport = "otlp_http"
+)
+
+// otlpMaxRetries and otlpRetryBaseDelay bound the backoff loop used by sendBatchOTLP.
+// These are intentionally local to the OTLP path; the generic disk-backed retry
+// path lives in buffer.go.
+const (
+	otlpMaxRetries     = 5
+	otlpRetryBaseDelay = 500 * time.Millisecond
+	otlpRetryMaxDelay  = 30 * time.Second
+)
+
+// severityNumber maps a HealOps LogLevel onto the OTLP Logs Data Model SeverityNumber.
+func severityNumber(level LogLevel) logspb.SeverityNumber {
+	switch level {
+	case InfoLevel:
+		return logspb.SeverityNumber_SEVERITY_NUMBER_INFO
+	case WarningLevel:
+		return logspb.SeverityNumber_SEVERITY_NUMBER_WARN
+	case ErrorLevel:
+		return logspb.SeverityNumber_SEVERITY_NUMBER_ERROR
+	case CriticalLevel:
+		return logspb.SeverityNumber_SEVERITY_NUMBER_FATAL
+	default:
+		return logspb.SeverityNumber_SEVERITY_NUMBER_UNSPECIFIED
+	}
+}
+
+// toAnyValue converts a loosely-typed metadata value into an OTLP AnyValue.
+func toAnyValue(v interface{}) *commonpb.AnyValue {
+	switch val := v.(type) {
+	case nil:
+		return &commonpb.AnyValue{}
+	case string:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: val}}
+	case bool:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_BoolValue{BoolValue: val}}
+	case int:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: int64(val)}}
+	case int64:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: val}}
+	case float64:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_DoubleValue{DoubleValue: val}}
+	default:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: fmt.Sprintf("%v", val)}}
+	}
+}
+
+// toOTLPLogRecord converts a LogPayload into an OTLP LogRecord, promoting
+// Metadata entries to record Attributes.
+func toOTLPLogRecord(payload LogPayload) *logspb.LogRecord {
+	ts, err := time.Parse(time.RFC3339, payload.Timestamp)
+	if err != nil {
+		ts = time.Now().UTC()
+	}
+
+	attrs := make([]*commonpb.KeyValue, 0, len(payload.Metadata))
+	for k, v := range payload.Metadata {
+		attrs = append(attrs, &commonpb.KeyValue{Key: k, Value: toAnyValue(v)})
+	}
+
+	return &logspb.LogRecord{
+		TimeUnixNano:         uint64(ts.UnixNano()),
+		ObservedTimeUnixNano: uint64(time.Now().UTC().UnixNano()),
+		SeverityNumber:       severityNumber(payload.Severity),
+		SeverityText:         string(payload.Severity),
+		Body:                 &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: payload.Message}},
+		Attributes:           attrs,
+	}
+}
+
+// buildExportLogsServiceRequest groups a batch of LogPayloads under a single
+// Resource (ServiceName/Environment/Release promoted to resource attributes).
+func (l *Logger) buildExportLogsServiceRequest(logs []LogPayload) *collogspb.ExportLogsServiceRequest {
+	resourceAttrs := []*commonpb.KeyValue{
+		{Key: "service.name", Value: toAnyValue(l.config.ServiceName)},
+	}
+	if l.config.Environment != "" {
+		resourceAttrs = append(resourceAttrs, &commonpb.KeyValue{Key: "deployment.environment", Value: toAnyValue(l.config.Environment)})
+	}
+	if l.config.Release != "" {
+		resourceAttrs = append(resourceAttrs, &commonpb.KeyValue{Key: "service.version", Value: toAnyValue(l.config.Release)})
+	}
+
+	records := make([]*logspb.LogRecord, 0, len(logs))
+	for _, payload := range logs {
+		records = append(records, toOTLPLogRecord(payload))
+	}
+
+	return &collogspb.ExportLogsServiceRequest{
+		ResourceLogs: []*logspb.ResourceLogs{
+			{
+				Resource: &resourcepb.Resource{Attributes: resourceAttrs},
+				ScopeLogs: []*logspb.ScopeLogs{
+					{LogRecords: records},
+				},
+			},
+		},
+	}
+}
+
+// otlpEndpoint returns the /v1/logs endpoint to POST OTLP batches to.
+func (l *Logger) otlpEndpoint() string {
+	if l.config.OTLPEndpoint != "" {
+		return l.config.OTLPEndpoint
+	}
+	return fmt.Sprintf("%s/v1/logs", l.config.Endpoint)
+}
+
+// sendBatchOTLP serializes logs as an OTLP ExportLogsServiceRequest and POSTs
+// it to /v1/logs, retrying on 429/5xx with exponential backoff + jitter. On
+// final failure it falls back to the disk buffer (or per-log sends),
+// mirroring sendBatch's failure handling for the HealOps JSON transport.
+func (l *Logger) sendBatchOTLP(logs []LogPayload) {
+	if len(logs) == 0 {
+		return
+	}
+
+	body, err := proto.Marshal(l.buildExportLogsServiceRequest(logs))
+	if err != nil {
+		if os.Getenv("HEALOPS_DEBUG") != "" {
+			fmt.Printf("Error marshalling OTLP log batch: %v\n", err)
+		}
+		return
+	}
+
+	if l.config.OTLPCompress {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		if _, err := gz.Write(body); err == nil && gz.Close() == nil {
+			body = buf.Bytes()
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	l.stats.addInFlight(1)
+	err = l.postOTLPWithRetry(ctx, body)
+	l.stats.addInFlight(-1)
+	if err != nil {
+		if os.Getenv("HEALOPS_DEBUG") != "" {
+			fmt.Printf("Error sending OTLP log batch: %v\n", err)
+		}
+		if l.buffer != nil {
+			if perr := l.buffer.Persist(logs); perr == nil {
+				l.stats.addPersisted(int64(len(logs)))
+				return
+			}
+		}
+		// A single-log batch is already a per-log send: count it dropped
+		// rather than falling back to itself.
+		if len(logs) == 1 {
+			l.stats.addDropped(1)
+			return
+		}
+		// No disk buffer (or spooling failed): fall back to best-effort
+		// per-log sends rather than lose the whole batch.
+		for _, log := range logs {
+			l.sendSingleLog(log)
+		}
+		return
+	}
+
+	if os.Getenv("HEALOPS_DEBUG") != "" {
+		fmt.Printf("HealOps flushed %d logs via OTLP/HTTP\n", len(logs))
+	}
+}
+
+// deliverBatchOTLP makes a single OTLP/HTTP delivery attempt for logs,
+// returning a Retry-After delay (if any) and error. It mirrors deliverBatch's
+// single-attempt contract so the sender's disk-backed retry loop can drive
+// either transport.
+func (l *Logger) deliverBatchOTLP(logs []LogPayload) (time.Duration, error) {
+	body, err := proto.Marshal(l.buildExportLogsServiceRequest(logs))
+	if err != nil {
+		return 0, err
+	}
+
+	if l.config.OTLPCompress {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		if _, err := gz.Write(body); err == nil && gz.Close() == nil {
+			body = buf.Bytes()
+		}
+	}
+
+	req, err := http.NewRequest("POST", l.otlpEndpoint(), bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	if l.config.OTLPCompress {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	req.Header.Set("X-HealOps-Key", l.config.APIKey)
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		var retryAfter time.Duration
+		if resp.StatusCode == 429 || resp.StatusCode == 503 {
+			if d, ok := retryAfterDelay(resp.Header.Get("Retry-After")); ok {
+				retryAfter = d
+			}
+		}
+		return retryAfter, fmt.Errorf("OTLP export failed, status: %s", resp.Status)
+	}
+
+	return 0, nil
+}
+
+func (l *Logger) postOTLPWithRetry(ctx context.Context, body []byte) error {
+	url := l.otlpEndpoint()
+	client := &http.Client{Timeout: 10 * time.Second}
+
+	var lastErr error
+	for attempt := 0; attempt < otlpMaxRetries; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/x-protobuf")
+		if l.config.OTLPCompress {
+			req.Header.Set("Content-Encoding", "gzip")
+		}
+		req.Header.Set("X-HealOps-Key", l.config.APIKey)
+
+		resp, err := client.Do(req)
+		if err != nil {
+			lastErr = err
+		} else {
+			resp.Body.Close()
+			if resp.StatusCode < 300 {
+				return nil
+			}
+			if resp.StatusCode != 429 && resp.StatusCode < 500 {
+				return fmt.Errorf("OTLP export failed, status: %s", resp.Status)
+			}
+			lastErr = fmt.Errorf("OTLP export failed, status: %s", resp.Status)
+			if delay, ok := retryAfterDelay(resp.Header.Get("Retry-After")); ok {
+				if !sleepWithContext(ctx, delay) {
+					return lastErr
+				}
+				continue
+			}
+		}
+
+		if !sleepWithContext(ctx, backoffWithJitter(attempt, otlpRetryBaseDelay, otlpRetryMaxDelay)) {
+			return lastErr
+		}
+	}
+
+	return lastErr
+}
+
+// retryAfterDelay parses a Retry-After header (seconds form only, which is
+// what HealOps's ingest endpoints emit).
+func retryAfterDelay(header string) (time.Duration, bool) {
+	if header == "" {
+		return 0, false
+	}
+	secs, err := strconv.Atoi(header)
+	if err != nil || secs < 0 {
+		return 0, false
+	}
+	return time.Duration(secs) * time.Second, true
+}
+
+// backoffWithJitter computes an exponential backoff delay capped at max, with
+// up to +/-20% jitter to avoid thundering herds.
+func backoffWithJitter(attempt int, base, max time.Duration) time.Duration {
+	delay := base << attempt
+	if delay <= 0 || delay > max {
+		delay = max
+	}
+	spread := int64(delay) * 2 / 5
+	jitter := time.Duration(rand.Int63n(spread + 1))
+	return delay - time.Duration(spread/2) + jitter
+}
+
+// sleepWithContext sleeps for d, returning false early if ctx is cancelled.
+func sleepWithContext(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,224 @@
+package healops
+
+import (
+	"container/list"
+	"context"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TailSampler is a sdktrace.SpanProcessor that implements tail-based
+// sampling: spans are buffered per TraceID until the trace's root span ends,
+// at which point the whole trace is kept (100%) if any span in it errored,
+// otherwise kept with probability SampleRate. Buffered traces that never see
+// their root end within TTL are evicted unexported, bounding memory.
+type TailSampler struct {
+	exporter   sdktrace.SpanExporter
+	sampleRate float64
+	maxTraces  int
+	ttl        time.Duration
+
+	mu       sync.Mutex
+	entries  map[trace.TraceID]*tailSamplerEntry
+	lru      *list.List // front = most recently touched
+	elements map[trace.TraceID]*list.Element
+
+	kept    int64
+	dropped int64
+	evicted int64
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+type tailSamplerEntry struct {
+	traceID   trace.TraceID
+	spans     []sdktrace.ReadOnlySpan
+	hasError  bool
+	rootEnded bool
+	expiresAt time.Time
+}
+
+// NewTailSampler creates a TailSampler that forwards kept traces to exporter.
+// maxTraces bounds the number of in-flight (not-yet-root-ended) traces kept
+// in memory; the oldest-touched trace is evicted once the cap is hit. ttl
+// bounds how long a trace can sit without its root span ending.
+func NewTailSampler(exporter sdktrace.SpanExporter, sampleRate float64, maxTraces int, ttl time.Duration) *TailSampler {
+	if maxTraces <= 0 {
+		maxTraces = 10000
+	}
+	if ttl <= 0 {
+		ttl = 30 * time.Second
+	}
+
+	ts := &TailSampler{
+		exporter:   exporter,
+		sampleRate: sampleRate,
+		maxTraces:  maxTraces,
+		ttl:        ttl,
+		entries:    make(map[trace.TraceID]*tailSamplerEntry),
+		lru:        list.New(),
+		elements:   make(map[trace.TraceID]*list.Element),
+		stop:       make(chan struct{}),
+	}
+
+	ts.wg.Add(1)
+	go ts.evictExpiredLoop()
+
+	return ts
+}
+
+// OnStart implements sdktrace.SpanProcessor. Buffering happens in OnEnd, once
+// a span's final attributes/status/events are available.
+func (ts *TailSampler) OnStart(parent context.Context, s sdktrace.ReadWriteSpan) {}
+
+// OnEnd implements sdktrace.SpanProcessor. It buffers s under its TraceID and,
+// once the root span of that trace ends, makes the keep/drop decision for the
+// whole trace.
+func (ts *TailSampler) OnEnd(s sdktrace.ReadOnlySpan) {
+	traceID := s.SpanContext().TraceID()
+	isRoot := !s.Parent().IsValid()
+	hasError := s.Status().Code == codes.Error
+	if !hasError {
+		for _, event := range s.Events() {
+			if event.Name == "exception" {
+				hasError = true
+				break
+			}
+		}
+	}
+
+	ts.mu.Lock()
+	entry, ok := ts.entries[traceID]
+	if !ok {
+		entry = &tailSamplerEntry{traceID: traceID}
+		ts.entries[traceID] = entry
+		ts.elements[traceID] = ts.lru.PushFront(traceID)
+		ts.enforceCapLocked()
+	} else {
+		ts.lru.MoveToFront(ts.elements[traceID])
+	}
+
+	entry.spans = append(entry.spans, s)
+	entry.hasError = entry.hasError || hasError
+	entry.expiresAt = time.Now().Add(ts.ttl)
+	if isRoot {
+		entry.rootEnded = true
+	}
+
+	var toExport []sdktrace.ReadOnlySpan
+	var keep bool
+	finished := entry.rootEnded
+	if finished {
+		keep = entry.hasError || rand.Float64() < ts.sampleRate
+		if keep {
+			toExport = entry.spans
+		}
+		ts.removeLocked(traceID)
+	}
+	ts.mu.Unlock()
+
+	if !finished {
+		return
+	}
+	if keep {
+		atomic.AddInt64(&ts.kept, 1)
+		_ = ts.exporter.ExportSpans(context.Background(), toExport)
+	} else {
+		atomic.AddInt64(&ts.dropped, 1)
+	}
+}
+
+// enforceCapLocked evicts the least-recently-touched trace(s) until the
+// buffer is back under maxTraces. Callers must hold ts.mu.
+func (ts *TailSampler) enforceCapLocked() {
+	for len(ts.entries) > ts.maxTraces {
+		oldest := ts.lru.Back()
+		if oldest == nil {
+			return
+		}
+		traceID := oldest.Value.(trace.TraceID)
+		ts.removeLocked(traceID)
+		atomic.AddInt64(&ts.evicted, 1)
+	}
+}
+
+// removeLocked drops a trace's buffered spans without exporting them.
+// Callers must hold ts.mu.
+func (ts *TailSampler) removeLocked(traceID trace.TraceID) {
+	delete(ts.entries, traceID)
+	if el, ok := ts.elements[traceID]; ok {
+		ts.lru.Remove(el)
+		delete(ts.elements, traceID)
+	}
+}
+
+func (ts *TailSampler) evictExpiredLoop() {
+	defer ts.wg.Done()
+
+	ticker := time.NewTicker(ts.ttl / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			ts.evictExpired()
+		case <-ts.stop:
+			return
+		}
+	}
+}
+
+func (ts *TailSampler) evictExpired() {
+	now := time.Now()
+
+	ts.mu.Lock()
+	var expired []trace.TraceID
+	for id, entry := range ts.entries {
+		if now.After(entry.expiresAt) {
+			expired = append(expired, id)
+		}
+	}
+	for _, id := range expired {
+		ts.removeLocked(id)
+	}
+	ts.mu.Unlock()
+
+	if len(expired) > 0 {
+		atomic.AddInt64(&ts.evicted, int64(len(expired)))
+	}
+}
+
+// Shutdown stops the eviction loop and shuts down the underlying exporter.
+func (ts *TailSampler) Shutdown(ctx context.Context) error {
+	close(ts.stop)
+	ts.wg.Wait()
+	return ts.exporter.Shutdown(ctx)
+}
+
+// ForceFlush is a no-op: buffered-but-unfinished traces have no complete
+// decision to export yet, and finished traces are exported synchronously in
+// OnEnd.
+func (ts *TailSampler) ForceFlush(ctx context.Context) error { return nil }
+
+// TailSamplerStats reports keep/drop/eviction counters for alerting.
+type TailSamplerStats struct {
+	Kept    int64
+	Dropped int64
+	Evicted int64
+}
+
+// Stats returns a snapshot of the sampler's keep/drop/eviction counters.
+func (ts *TailSampler) Stats() TailSamplerStats {
+	return TailSamplerStats{
+		Kept:    atomic.LoadInt64(&ts.kept),
+		Dropped: atomic.LoadInt64(&ts.dropped),
+		Evicted: atomic.LoadInt64(&ts.evicted),
+	}
+}
@@ -0,0 +1,151 @@
+package healops
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// newTailSamplerTracer wires sampler into a TracerProvider as its only
+// SpanProcessor, so ending a span started from the returned tracer feeds the
+// sampler a real sdktrace.ReadOnlySpan via OnEnd. The TracerProvider (and
+// with it, the sampler) is shut down when the test ends.
+func newTailSamplerTracer(t *testing.T, sampler *TailSampler) trace.Tracer {
+	t.Helper()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSampler(sdktrace.AlwaysSample()), sdktrace.WithSpanProcessor(sampler))
+	t.Cleanup(func() { tp.Shutdown(context.Background()) })
+	return tp.Tracer("test")
+}
+
+// endRootSpan starts and ends a standalone root span (no parent), so the
+// sampler sees isRoot true and makes its keep/drop decision immediately.
+func endRootSpan(tracer trace.Tracer, name string, withError bool) {
+	_, span := tracer.Start(context.Background(), name)
+	if withError {
+		span.SetStatus(codes.Error, "boom")
+	}
+	span.End()
+}
+
+// endChildSpan ends a span whose parent context carries a synthetic,
+// already-valid SpanContext for traceID, so the sampler sees isRoot false and
+// leaves the trace buffered (no keep/drop decision made).
+func endChildSpan(tracer trace.Tracer, traceID trace.TraceID, name string) {
+	var spanID trace.SpanID
+	spanID[0] = 1
+	parentSC := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+		Remote:     true,
+	})
+	ctx := trace.ContextWithRemoteSpanContext(context.Background(), parentSC)
+
+	_, span := tracer.Start(ctx, name)
+	span.End()
+}
+
+func traceIDFromByte(b byte) trace.TraceID {
+	var id trace.TraceID
+	id[len(id)-1] = b
+	return id
+}
+
+func TestTailSamplerKeepsErroredTraces(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	sampler := NewTailSampler(exporter, 0, 10, time.Minute)
+	tracer := newTailSamplerTracer(t, sampler)
+
+	endRootSpan(tracer, "root", true)
+
+	if got := len(exporter.GetSpans()); got != 1 {
+		t.Fatalf("errored trace: got %d exported spans, want 1", got)
+	}
+	if stats := sampler.Stats(); stats.Kept != 1 || stats.Dropped != 0 {
+		t.Fatalf("errored trace: got stats %+v, want Kept=1 Dropped=0", stats)
+	}
+}
+
+func TestTailSamplerDropsNonErroredTracesAtZeroSampleRate(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	sampler := NewTailSampler(exporter, 0, 10, time.Minute)
+	tracer := newTailSamplerTracer(t, sampler)
+
+	endRootSpan(tracer, "root", false)
+
+	if got := len(exporter.GetSpans()); got != 0 {
+		t.Fatalf("non-errored trace: got %d exported spans, want 0", got)
+	}
+	if stats := sampler.Stats(); stats.Kept != 0 || stats.Dropped != 1 {
+		t.Fatalf("non-errored trace: got stats %+v, want Kept=0 Dropped=1", stats)
+	}
+}
+
+func TestTailSamplerKeepsAtFullSampleRate(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	sampler := NewTailSampler(exporter, 1, 10, time.Minute)
+	tracer := newTailSamplerTracer(t, sampler)
+
+	endRootSpan(tracer, "root", false)
+
+	if got := len(exporter.GetSpans()); got != 1 {
+		t.Fatalf("full sample rate: got %d exported spans, want 1", got)
+	}
+}
+
+func TestTailSamplerEvictsOldestOnCap(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	sampler := NewTailSampler(exporter, 0, 1, time.Minute)
+	tracer := newTailSamplerTracer(t, sampler)
+
+	traceA := traceIDFromByte(1)
+	traceB := traceIDFromByte(2)
+
+	// Both are non-root spans, so neither finishes its trace; the second
+	// insertion should push the cap and evict traceA's buffered entry.
+	endChildSpan(tracer, traceA, "a-child")
+	endChildSpan(tracer, traceB, "b-child")
+
+	sampler.mu.Lock()
+	_, aStillBuffered := sampler.entries[traceA]
+	_, bStillBuffered := sampler.entries[traceB]
+	sampler.mu.Unlock()
+
+	if aStillBuffered {
+		t.Fatal("expected oldest trace to be evicted, but it's still buffered")
+	}
+	if !bStillBuffered {
+		t.Fatal("expected most recently touched trace to remain buffered")
+	}
+	if stats := sampler.Stats(); stats.Evicted != 1 {
+		t.Fatalf("got Evicted=%d, want 1", stats.Evicted)
+	}
+}
+
+func TestTailSamplerEvictsExpiredTraces(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	sampler := NewTailSampler(exporter, 0, 10, 20*time.Millisecond)
+	tracer := newTailSamplerTracer(t, sampler)
+
+	traceA := traceIDFromByte(3)
+	endChildSpan(tracer, traceA, "a-child")
+
+	time.Sleep(30 * time.Millisecond)
+	sampler.evictExpired()
+
+	sampler.mu.Lock()
+	_, stillBuffered := sampler.entries[traceA]
+	sampler.mu.Unlock()
+
+	if stillBuffered {
+		t.Fatal("expected expired trace to be evicted")
+	}
+	if stats := sampler.Stats(); stats.Evicted != 1 {
+		t.Fatalf("got Evicted=%d, want 1", stats.Evicted)
+	}
+}